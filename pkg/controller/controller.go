@@ -2,18 +2,31 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"log"
-	"reflect"
+	"sort"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/workqueue"
 )
 
 func ignoreNotFound(err error) error {
@@ -25,32 +38,183 @@ func ignoreNotFound(err error) error {
 
 const resyncPeriod = time.Second * 30
 
+// Annotations that let individual Pods/Jobs opt out of cleanup entirely, or
+// override the operator-wide retention windows with their own TTL.
+const (
+	annotationIgnore      = "kube-cleanup-operator.io/ignore"
+	annotationTTLOverride = "kube-cleanup-operator.io/ttl-override"
+)
+
+// isIgnored reports whether the object carries the ignore annotation set to
+// "true", in which case the operator must never touch it regardless of age.
+func isIgnored(meta metav1.Object) bool {
+	return meta.GetAnnotations()[annotationIgnore] == "true"
+}
+
+// ttlOverride parses the per-object ttl-override annotation, if present,
+// returning the duration it requests and whether the annotation was set to a
+// valid duration; an unparsable value is logged and treated as unset.
+func ttlOverride(meta metav1.Object) (time.Duration, bool) {
+	val, ok := meta.GetAnnotations()[annotationTTLOverride]
+	if !ok {
+		return 0, false
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		log.Printf("ignoring invalid %s annotation %q on %s: %v", annotationTTLOverride, val, meta.GetName(), err)
+		return 0, false
+	}
+	return d, true
+}
+
+// disruptionTargetCondition is the pod condition type kubelet/PodGC/the
+// scheduler set on pods being torn down by preemption, taint eviction, the
+// Eviction API, or PodGC, ahead of them landing in the Failed phase.
+const disruptionTargetCondition = "DisruptionTarget"
+
+// disruptedPodsTotal counts pods reaped because they carried a
+// DisruptionTarget condition, broken down by the condition's Reason so
+// operators can tell preemption apart from taint- or GC-driven churn.
+var disruptedPodsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "kube_cleanup_operator_disrupted_pods_total",
+	Help: "Number of pods deleted because of a DisruptionTarget condition, by reason.",
+}, []string{"reason"})
+
+// extractDisruptionTarget looks for a true DisruptionTarget condition on the
+// pod and, if found, returns its Reason and the time it was set.
+func extractDisruptionTarget(podObj *corev1.Pod) (reason string, transitionTime time.Time, ok bool) {
+	for _, pc := range podObj.Status.Conditions {
+		if string(pc.Type) == disruptionTargetCondition && pc.Status == corev1.ConditionTrue {
+			return pc.Reason, pc.LastTransitionTime.Time, true
+		}
+	}
+	return "", time.Time{}, false
+}
+
+// Config holds the construction options for a Kleaner. It grew out of
+// NewKleaner's parameter list once that list stopped being readable at the
+// call site.
+type Config struct {
+	Namespace     string
+	LabelSelector labels.Selector
+	FieldSelector fields.Selector
+	DryRun        bool
+
+	DeleteSuccessfulAfter time.Duration
+	DeleteFailedAfter     time.Duration
+	DeletePendingAfter    time.Duration
+	DeleteOrphanedAfter   time.Duration
+	DeleteDisruptedAfter  time.Duration
+
+	EvictionMode bool
+	// GracePeriodSeconds overrides the grace period on the direct-delete and
+	// eviction paths. Its zero value (nil) is "no override" — leave the
+	// server/pod's own default in place — rather than "terminate
+	// immediately", so a caller that forgets to set it doesn't silently
+	// force ungraceful termination of every pod. Pass a pointer to 0 to
+	// request immediate termination explicitly.
+	GracePeriodSeconds    *int64
+	EvictionTimeout       time.Duration
+	EvictionForceFallback bool
+
+	// RespectCronJobHistory skips cleanup of Jobs owned by a CronJob,
+	// leaving them to that CronJob's own successfulJobsHistoryLimit /
+	// failedJobsHistoryLimit instead of fighting the built-in controller.
+	RespectCronJobHistory bool
+	// KeepLastNPerOwner, when greater than zero, retains only the newest N
+	// completed Jobs per owner UID (useful for owners, e.g. Argo/Tekton
+	// workflows, that don't prune their own Job history) and deletes the
+	// rest regardless of age, except Jobs carrying the ignore annotation.
+	KeepLastNPerOwner int
+
+	// Workers is the number of goroutines draining the work queue.
+	Workers int
+
+	// LeaderElection, when true, wraps Run in client-go leader election so
+	// multiple replicas can be deployed HA without racing on deletes.
+	LeaderElection          bool
+	LeaderElectionNamespace string
+	LeaderElectionID        string
+	// Identity identifies this process in the leader election lock record,
+	// e.g. the pod name.
+	Identity string
+}
+
 // Kleaner watches the kubernetes api for changes to Pods and
 // delete completed Pods without specific annotation
 type Kleaner struct {
 	podInformer cache.SharedIndexInformer
 	jobInformer cache.SharedIndexInformer
-	kclient     *kubernetes.Clientset
+	kclient     kubernetes.Interface
+	queue       workqueue.RateLimitingInterface
 
 	deleteSuccessfulAfter time.Duration
 	deleteFailedAfter     time.Duration
 	deletePendingAfter    time.Duration
 	deleteOrphanedAfter   time.Duration
+	deleteDisruptedAfter  time.Duration
+
+	labelSelector labels.Selector
+	fieldSelector fields.Selector
+
+	evictionMode          bool
+	evictionTimeout       time.Duration
+	evictionForceFallback bool
+	gracePeriodSeconds    *int64
+
+	respectCronJobHistory bool
+	keepLastNPerOwner     int
+
+	workers                 int
+	leaderElection          bool
+	leaderElectionNamespace string
+	leaderElectionID        string
+	identity                string
 
 	dryRun bool
 	ctx    context.Context
 }
 
+// queueKey identifies a queued object by the informer it came from and its
+// namespace/name, so a single work queue can multiplex Pods and Jobs.
+type queueKey struct {
+	kind string
+	key  string
+}
+
+// BuildClientset builds a *kubernetes.Clientset whose outbound request rate
+// against the apiserver is capped at qps/burst via client-go's flowcontrol
+// rate limiter, instead of client-go's conservative built-in defaults. Use
+// this instead of kubernetes.NewForConfig directly so that a large cleanup
+// backlog (e.g. catching up after an outage) can't fire a burst of
+// delete/evict calls large enough to overwhelm the apiserver.
+func BuildClientset(restConfig *rest.Config, qps float32, burst int) (*kubernetes.Clientset, error) {
+	cfg := *restConfig
+	cfg.QPS = qps
+	cfg.Burst = burst
+	cfg.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+	return kubernetes.NewForConfig(&cfg)
+}
+
 // NewKleaner creates a new NewKleaner
-func NewKleaner(ctx context.Context, kclient *kubernetes.Clientset, namespace string, dryRun bool, deleteSuccessfulAfter,
-	deleteFailedAfter, deletePendingAfter, deleteOrphanedAfter time.Duration) *Kleaner {
+func NewKleaner(ctx context.Context, kclient kubernetes.Interface, cfg Config) *Kleaner {
+	listOptions := func(options *metav1.ListOptions) {
+		if cfg.LabelSelector != nil {
+			options.LabelSelector = cfg.LabelSelector.String()
+		}
+		if cfg.FieldSelector != nil {
+			options.FieldSelector = cfg.FieldSelector.String()
+		}
+	}
 	jobInformer := cache.NewSharedIndexInformer(
 		&cache.ListWatch{
 			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
-				return kclient.BatchV1().Jobs(namespace).List(ctx, options)
+				listOptions(&options)
+				return kclient.BatchV1().Jobs(cfg.Namespace).List(ctx, options)
 			},
 			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
-				return kclient.BatchV1().Jobs(namespace).Watch(ctx, options)
+				listOptions(&options)
+				return kclient.BatchV1().Jobs(cfg.Namespace).Watch(ctx, options)
 			},
 		},
 		&batchv1.Job{},
@@ -61,10 +225,12 @@ func NewKleaner(ctx context.Context, kclient *kubernetes.Clientset, namespace st
 	podInformer := cache.NewSharedIndexInformer(
 		&cache.ListWatch{
 			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
-				return kclient.CoreV1().Pods(namespace).List(ctx, options)
+				listOptions(&options)
+				return kclient.CoreV1().Pods(cfg.Namespace).List(ctx, options)
 			},
 			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
-				return kclient.CoreV1().Pods(namespace).Watch(ctx, options)
+				listOptions(&options)
+				return kclient.CoreV1().Pods(cfg.Namespace).Watch(ctx, options)
 			},
 		},
 		&corev1.Pod{},
@@ -72,33 +238,39 @@ func NewKleaner(ctx context.Context, kclient *kubernetes.Clientset, namespace st
 		cache.Indexers{},
 	)
 	kleaner := &Kleaner{
-		dryRun:                dryRun,
-		kclient:               kclient,
-		ctx:                   ctx,
-		deleteSuccessfulAfter: deleteSuccessfulAfter,
-		deleteFailedAfter:     deleteFailedAfter,
-		deletePendingAfter:    deletePendingAfter,
-		deleteOrphanedAfter:   deleteOrphanedAfter,
+		dryRun:                  cfg.DryRun,
+		kclient:                 kclient,
+		ctx:                     ctx,
+		queue:                   workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		deleteSuccessfulAfter:   cfg.DeleteSuccessfulAfter,
+		deleteFailedAfter:       cfg.DeleteFailedAfter,
+		deletePendingAfter:      cfg.DeletePendingAfter,
+		deleteOrphanedAfter:     cfg.DeleteOrphanedAfter,
+		deleteDisruptedAfter:    cfg.DeleteDisruptedAfter,
+		labelSelector:           cfg.LabelSelector,
+		fieldSelector:           cfg.FieldSelector,
+		evictionMode:            cfg.EvictionMode,
+		gracePeriodSeconds:      cfg.GracePeriodSeconds,
+		evictionTimeout:         cfg.EvictionTimeout,
+		evictionForceFallback:   cfg.EvictionForceFallback,
+		respectCronJobHistory:   cfg.RespectCronJobHistory,
+		keepLastNPerOwner:       cfg.KeepLastNPerOwner,
+		workers:                 cfg.Workers,
+		leaderElection:          cfg.LeaderElection,
+		leaderElectionNamespace: cfg.LeaderElectionNamespace,
+		leaderElectionID:        cfg.LeaderElectionID,
+		identity:                cfg.Identity,
+	}
+	if kleaner.workers <= 0 {
+		kleaner.workers = 1
 	}
 	jobInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: func(obj interface{}) {
-			kleaner.Process(obj)
-		},
-		UpdateFunc: func(old, new interface{}) {
-			if !reflect.DeepEqual(old, new) {
-				kleaner.Process(new)
-			}
-		},
+		AddFunc:    kleaner.enqueueJob,
+		UpdateFunc: func(old, new interface{}) { kleaner.enqueueJob(new) },
 	})
 	podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: func(obj interface{}) {
-			kleaner.Process(obj)
-		},
-		UpdateFunc: func(old, new interface{}) {
-			if !reflect.DeepEqual(old, new) {
-				kleaner.Process(new)
-			}
-		},
+		AddFunc:    kleaner.enqueuePod,
+		UpdateFunc: func(old, new interface{}) { kleaner.enqueuePod(new) },
 	})
 
 	kleaner.podInformer = podInformer
@@ -107,53 +279,201 @@ func NewKleaner(ctx context.Context, kclient *kubernetes.Clientset, namespace st
 	return kleaner
 }
 
-func (c *Kleaner) periodicCacheCheck() {
-	for {
-		for _, job := range c.jobInformer.GetStore().List() {
-			c.Process(job)
-		}
-		for _, obj := range c.podInformer.GetStore().List() {
-			c.Process(obj)
-		}
-		time.Sleep(2 * resyncPeriod)
+func (c *Kleaner) enqueueJob(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		log.Printf("failed to compute key for job: %v", err)
+		return
 	}
+	c.queue.Add(queueKey{kind: "job", key: key})
 }
 
-// Run starts the process for listening for pod changes and acting upon those changes.
+func (c *Kleaner) enqueuePod(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		log.Printf("failed to compute key for pod: %v", err)
+		return
+	}
+	c.queue.Add(queueKey{kind: "pod", key: key})
+}
+
+// Run starts the informers and the work queue workers, optionally wrapped in
+// leader election so only one of several replicas is ever active.
 func (c *Kleaner) Run(stopCh <-chan struct{}) {
+	if !c.leaderElection {
+		c.run(stopCh)
+		return
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      c.leaderElectionID,
+			Namespace: c.leaderElectionNamespace,
+		},
+		Client: c.kclient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: c.identity,
+		},
+	}
+	leaderelection.RunOrDie(c.ctx, leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		ReleaseOnCancel: true,
+		LeaseDuration:   15 * time.Second,
+		RenewDeadline:   10 * time.Second,
+		RetryPeriod:     2 * time.Second,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				log.Printf("%s: started leading", c.identity)
+				// run must stop the moment this replica loses its lease, not
+				// just when the outer stopCh closes, otherwise it keeps
+				// deleting pods/jobs alongside the new leader.
+				leaderStopCh := make(chan struct{})
+				go func() {
+					select {
+					case <-ctx.Done():
+					case <-stopCh:
+					}
+					close(leaderStopCh)
+				}()
+				c.run(leaderStopCh)
+			},
+			OnStoppedLeading: func() {
+				log.Printf("%s: stopped leading", c.identity)
+			},
+		},
+	})
+}
+
+func (c *Kleaner) run(stopCh <-chan struct{}) {
 	log.Printf("Listening for changes...")
+	defer c.queue.ShutDown()
 
 	go c.podInformer.Run(stopCh)
 	go c.jobInformer.Run(stopCh)
 
-	go c.periodicCacheCheck()
+	if !cache.WaitForCacheSync(stopCh, c.podInformer.HasSynced, c.jobInformer.HasSynced) {
+		log.Printf("timed out waiting for informer caches to sync")
+		return
+	}
+
+	for i := 0; i < c.workers; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
 
 	<-stopCh
 }
 
+func (c *Kleaner) runWorker() {
+	for c.processNextQueueItem() {
+	}
+}
+
+// processNextQueueItem pops a single item off the work queue and processes
+// it, looking the current object up in the relevant informer's store so
+// handlers never act on stale data held in the queue itself.
+func (c *Kleaner) processNextQueueItem() bool {
+	item, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(item)
+
+	qk := item.(queueKey)
+	if err := c.processQueueKey(qk); err != nil {
+		log.Printf("failed to process %s %q, retrying: %v", qk.kind, qk.key, err)
+		c.queue.AddRateLimited(item)
+		return true
+	}
+	c.queue.Forget(item)
+	return true
+}
+
+func (c *Kleaner) processQueueKey(qk queueKey) error {
+	var (
+		obj    interface{}
+		exists bool
+		err    error
+	)
+	switch qk.kind {
+	case "job":
+		obj, exists, err = c.jobInformer.GetStore().GetByKey(qk.key)
+	case "pod":
+		obj, exists, err = c.podInformer.GetStore().GetByKey(qk.key)
+	default:
+		return fmt.Errorf("unknown queue item kind %q", qk.kind)
+	}
+	if err != nil {
+		return err
+	}
+	if !exists {
+		// Deleted since it was enqueued; nothing to clean up.
+		return nil
+	}
+	c.Process(obj)
+	return nil
+}
+
 func (c *Kleaner) Process(obj interface{}) {
 	switch t := obj.(type) {
 	case *batchv1.Job:
 		job := t
+		if isIgnored(job) {
+			log.Printf("Job %s carries the %s annotation, skipping", job.Name, annotationIgnore)
+			return
+		}
+		if c.respectCronJobHistory && jobOwnedByCronJob(job) {
+			log.Printf("Job %s is owned by a CronJob, leaving cleanup to its history limits", job.Name)
+			return
+		}
 		log.Printf("Found a job: %s. completionTime: %v active: %v", job.Name, job.Status.CompletionTime, job.Status.Active)
 		// skip the job if it hasn't completed yet or has any active pods
 		if job.Status.CompletionTime.IsZero() || job.Status.Active > 0 {
 			return
 		}
+		if c.keepLastNPerOwner > 0 && c.pruneJobsByOwner(job) {
+			// job itself was among the pruned stale jobs; nothing left to do.
+			return
+		}
 		timeSinceCompletion := time.Now().Sub(job.Status.CompletionTime.Time)
+		successfulAfter, failedAfter := c.deleteSuccessfulAfter, c.deleteFailedAfter
+		if ttl, ok := ttlOverride(job); ok {
+			successfulAfter, failedAfter = ttl, ttl
+		}
 		if job.Status.Succeeded > 0 {
-			if c.deleteSuccessfulAfter > 0 && timeSinceCompletion > c.deleteSuccessfulAfter {
+			if successfulAfter > 0 && timeSinceCompletion > successfulAfter {
 				c.deleteJobs(job)
 			}
 		}
 		if job.Status.Failed > 0 {
-			if c.deleteFailedAfter > 0 && timeSinceCompletion >= c.deleteFailedAfter {
+			if failedAfter > 0 && timeSinceCompletion >= failedAfter {
 				c.deleteJobs(job)
 			}
 		}
 
 	case *corev1.Pod:
 		pod := t
+		if isIgnored(pod) {
+			log.Printf("Pod %s carries the %s annotation, skipping", pod.Name, annotationIgnore)
+			return
+		}
+		// DisruptionTarget pods (preemption, taint eviction, the Eviction API,
+		// PodGC) are their own retention class, independent of deleteOrphanedAfter
+		// and regardless of pod ownership: most disrupted pods belong to a
+		// ReplicaSet/Deployment/StatefulSet, not a Job.
+		if reason, transitionTime, ok := extractDisruptionTarget(pod); ok {
+			disruptedAfter := c.deleteDisruptedAfter
+			if ttl, ok := ttlOverride(pod); ok {
+				disruptedAfter = ttl
+			}
+			age := time.Now().Sub(transitionTime)
+			log.Printf("Found a disrupted pod: %s. reason: %s age: %v", pod.Name, reason, age)
+			if disruptedAfter > 0 && age >= disruptedAfter {
+				if err := c.deletePods(pod); err == nil && !c.dryRun {
+					disruptedPodsTotal.WithLabelValues(reason).Inc()
+				}
+			}
+			return
+		}
 		ownedByJob := podOwnedByJob(pod)
 		log.Printf("Found a pod: %s. owned by job %v", pod.Name, ownedByJob)
 		if !ownedByJob && c.deleteOrphanedAfter == 0 {
@@ -165,17 +485,21 @@ func (c *Kleaner) Process(obj interface{}) {
 		}
 		age := time.Now().Sub(podFinishTime)
 		log.Printf("Found a pod: %s. completionTime: %v age: %v", pod.Name, podFinishTime, age)
+		successfulAfter, failedAfter, pendingAfter := c.deleteSuccessfulAfter, c.deleteFailedAfter, c.deletePendingAfter
+		if ttl, ok := ttlOverride(pod); ok {
+			successfulAfter, failedAfter, pendingAfter = ttl, ttl, ttl
+		}
 		switch pod.Status.Phase {
 		case corev1.PodSucceeded:
-			if c.deleteSuccessfulAfter > 0 && age >= c.deleteSuccessfulAfter {
+			if successfulAfter > 0 && age >= successfulAfter {
 				c.deletePods(pod)
 			}
 		case corev1.PodFailed:
-			if c.deleteFailedAfter > 0 && age >= c.deleteFailedAfter {
+			if failedAfter > 0 && age >= failedAfter {
 				c.deletePods(pod)
 			}
 		case corev1.PodPending:
-			if c.deletePendingAfter > 0 && age >= c.deletePendingAfter {
+			if pendingAfter > 0 && age >= pendingAfter {
 				c.deletePods(pod)
 			}
 		default:
@@ -197,15 +521,86 @@ func (c *Kleaner) deleteJobs(job *batchv1.Job) {
 	}
 }
 
-func (c *Kleaner) deletePods(pod *corev1.Pod) {
+// deletePods removes pod via the configured deletion path (direct delete or
+// eviction), returning the error from that path so callers that count
+// confirmed deletions (e.g. the DisruptionTarget reason counter) don't count
+// attempts that actually failed.
+func (c *Kleaner) deletePods(pod *corev1.Pod) error {
 	if c.dryRun {
 		log.Printf("dry-run: Pod '%s:%s' would have been deleted", pod.Namespace, pod.Name)
+		return nil
+	}
+	if c.evictionMode {
+		return c.evictPod(pod)
 	}
+	return c.deletePod(pod, c.gracePeriodSeconds)
+}
+
+// deletePod performs a direct Pods().Delete, honoring the configurable
+// grace period instead of always falling back to the server default.
+// gracePeriodSeconds may be nil to leave the server/pod default in place.
+func (c *Kleaner) deletePod(pod *corev1.Pod, gracePeriodSeconds *int64) error {
 	log.Printf("Deleting pod '%s:%s'", pod.Namespace, pod.Name)
-	var po metav1.DeleteOptions
-	if err := c.kclient.CoreV1().Pods(pod.Namespace).Delete(c.ctx, pod.Name, po); ignoreNotFound(err) != nil {
+	po := metav1.DeleteOptions{GracePeriodSeconds: gracePeriodSeconds}
+	err := ignoreNotFound(c.kclient.CoreV1().Pods(pod.Namespace).Delete(c.ctx, pod.Name, po))
+	if err != nil {
 		log.Printf("failed to delete pod '%s:%s': %v", pod.Namespace, pod.Name, err)
 	}
+	return err
+}
+
+// evictPod submits a policy/v1 Eviction for the pod instead of deleting it
+// directly, so PodDisruptionBudgets and terminationGracePeriodSeconds are
+// respected. It retries with exponential backoff while the apiserver
+// responds with 429 TooManyRequests (the eviction is blocked by a PDB), up
+// to evictionTimeout, and optionally force-deletes the pod if it still
+// can't be evicted by then.
+func (c *Kleaner) evictPod(pod *corev1.Pod) error {
+	log.Printf("Evicting pod '%s:%s'", pod.Namespace, pod.Name)
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	}
+	if c.gracePeriodSeconds != nil {
+		eviction.DeleteOptions = &metav1.DeleteOptions{GracePeriodSeconds: c.gracePeriodSeconds}
+	}
+
+	// Allow only as many attempts as fit in evictionTimeout: the delays
+	// double from one second and their sum never exceeds the timeout.
+	steps := 1
+	for total, d := time.Duration(0), time.Second; total+d <= c.evictionTimeout; d *= 2 {
+		total += d
+		steps++
+	}
+	backoff := wait.Backoff{
+		Duration: time.Second,
+		Factor:   2,
+		Steps:    steps,
+	}
+	err := wait.ExponentialBackoff(backoff, func() (bool, error) {
+		err := c.kclient.PolicyV1().Evictions(pod.Namespace).Evict(c.ctx, eviction)
+		if apierrs.IsTooManyRequests(err) {
+			log.Printf("eviction of pod '%s:%s' blocked by a PodDisruptionBudget, retrying", pod.Namespace, pod.Name)
+			return false, nil
+		}
+		return true, ignoreNotFound(err)
+	})
+	if err == nil {
+		return nil
+	}
+	if err != wait.ErrWaitTimeout {
+		log.Printf("failed to evict pod '%s:%s': %v", pod.Namespace, pod.Name, err)
+		return err
+	}
+	log.Printf("timed out evicting pod '%s:%s' after %s", pod.Namespace, pod.Name, c.evictionTimeout)
+	if !c.evictionForceFallback {
+		return err
+	}
+	log.Printf("force-deleting pod '%s:%s' after eviction timeout", pod.Namespace, pod.Name)
+	immediate := int64(0)
+	return c.deletePod(pod, &immediate)
 }
 
 func podOwnedByJob(pod *corev1.Pod) bool {
@@ -218,6 +613,69 @@ func podOwnedByJob(pod *corev1.Pod) bool {
 	return false
 }
 
+func jobOwnedByCronJob(job *batchv1.Job) bool {
+	for _, ow := range job.OwnerReferences {
+		if ow.Kind == "CronJob" {
+			return true
+		}
+	}
+	return false
+}
+
+// firstOwnerUID returns the UID of the object's first owner reference, the
+// same "usually there is only one owner" assumption podOwnedByJob makes.
+func firstOwnerUID(obj metav1.Object) (types.UID, bool) {
+	owners := obj.GetOwnerReferences()
+	if len(owners) == 0 {
+		return "", false
+	}
+	return owners[0].UID, true
+}
+
+// pruneJobsByOwner keeps only the newest keepLastNPerOwner completed Jobs
+// that share job's owner UID, deleting the rest except those carrying the
+// ignore annotation (they still count toward the newest N). This covers Jobs deployed
+// by controllers (e.g. Argo/Tekton workflows) that don't prune their own
+// Job history the way CronJob does. It reports whether job itself was one
+// of the ones deleted, so the caller doesn't try to process it again.
+func (c *Kleaner) pruneJobsByOwner(job *batchv1.Job) bool {
+	owner, ok := firstOwnerUID(job)
+	if !ok {
+		return false
+	}
+
+	var siblings []*batchv1.Job
+	for _, obj := range c.jobInformer.GetStore().List() {
+		sibling, ok := obj.(*batchv1.Job)
+		if !ok || sibling.Status.CompletionTime.IsZero() {
+			continue
+		}
+		if siblingOwner, ok := firstOwnerUID(sibling); !ok || siblingOwner != owner {
+			continue
+		}
+		siblings = append(siblings, sibling)
+	}
+	if len(siblings) <= c.keepLastNPerOwner {
+		return false
+	}
+
+	sort.Slice(siblings, func(i, j int) bool {
+		return siblings[i].Status.CompletionTime.After(siblings[j].Status.CompletionTime.Time)
+	})
+	deletedSelf := false
+	for _, stale := range siblings[c.keepLastNPerOwner:] {
+		if isIgnored(stale) {
+			continue
+		}
+		log.Printf("Job %s exceeds keep-last-%d-per-owner for owner %s, deleting", stale.Name, c.keepLastNPerOwner, owner)
+		c.deleteJobs(stale)
+		if stale.UID == job.UID {
+			deletedSelf = true
+		}
+	}
+	return deletedSelf
+}
+
 func extractPodFinishTime(podObj *corev1.Pod) time.Time {
 	for _, pc := range podObj.Status.Conditions {
 		// Looking for the time when pod's condition "Ready" became "false" (equals end of execution)
@@ -0,0 +1,276 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestIsIgnored(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{
+		{name: "no annotations", annotations: nil, want: false},
+		{name: "ignore true", annotations: map[string]string{annotationIgnore: "true"}, want: true},
+		{name: "ignore false", annotations: map[string]string{annotationIgnore: "false"}, want: false},
+		{name: "ignore garbage", annotations: map[string]string{annotationIgnore: "yes"}, want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if got := isIgnored(pod); got != tc.want {
+				t.Errorf("isIgnored() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTTLOverride(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		wantOK      bool
+		want        time.Duration
+	}{
+		{name: "unset", annotations: nil, wantOK: false},
+		{name: "valid", annotations: map[string]string{annotationTTLOverride: "5m"}, wantOK: true, want: 5 * time.Minute},
+		{name: "invalid", annotations: map[string]string{annotationTTLOverride: "banana"}, wantOK: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			got, ok := ttlOverride(job)
+			if ok != tc.wantOK {
+				t.Fatalf("ttlOverride() ok = %v, want %v", ok, tc.wantOK)
+			}
+			if ok && got != tc.want {
+				t.Errorf("ttlOverride() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExtractDisruptionTarget(t *testing.T) {
+	transitionTime := metav1.NewTime(time.Now().Add(-time.Hour))
+	cases := []struct {
+		name       string
+		conditions []corev1.PodCondition
+		wantOK     bool
+		wantReason string
+	}{
+		{name: "no conditions", conditions: nil, wantOK: false},
+		{
+			name: "disruption target true",
+			conditions: []corev1.PodCondition{
+				{Type: "DisruptionTarget", Status: corev1.ConditionTrue, Reason: "PreemptionByKubeScheduler", LastTransitionTime: transitionTime},
+			},
+			wantOK:     true,
+			wantReason: "PreemptionByKubeScheduler",
+		},
+		{
+			name: "disruption target false",
+			conditions: []corev1.PodCondition{
+				{Type: "DisruptionTarget", Status: corev1.ConditionFalse, Reason: "PreemptionByKubeScheduler"},
+			},
+			wantOK: false,
+		},
+		{
+			name: "unrelated condition",
+			conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+			},
+			wantOK: false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{Status: corev1.PodStatus{Conditions: tc.conditions}}
+			reason, transition, ok := extractDisruptionTarget(pod)
+			if ok != tc.wantOK {
+				t.Fatalf("extractDisruptionTarget() ok = %v, want %v", ok, tc.wantOK)
+			}
+			if ok && reason != tc.wantReason {
+				t.Errorf("extractDisruptionTarget() reason = %q, want %q", reason, tc.wantReason)
+			}
+			if ok && !transition.Equal(transitionTime.Time) {
+				t.Errorf("extractDisruptionTarget() transition = %v, want %v", transition, transitionTime.Time)
+			}
+		})
+	}
+}
+
+func TestJobOwnedByCronJob(t *testing.T) {
+	cases := []struct {
+		name   string
+		owners []metav1.OwnerReference
+		want   bool
+	}{
+		{name: "no owner", owners: nil, want: false},
+		{name: "owned by cronjob", owners: []metav1.OwnerReference{{Kind: "CronJob"}}, want: true},
+		{name: "owned by something else", owners: []metav1.OwnerReference{{Kind: "Workflow"}}, want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{OwnerReferences: tc.owners}}
+			if got := jobOwnedByCronJob(job); got != tc.want {
+				t.Errorf("jobOwnedByCronJob() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFirstOwnerUID(t *testing.T) {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		OwnerReferences: []metav1.OwnerReference{{UID: types.UID("owner-1")}, {UID: types.UID("owner-2")}},
+	}}
+	uid, ok := firstOwnerUID(job)
+	if !ok || uid != types.UID("owner-1") {
+		t.Fatalf("firstOwnerUID() = (%v, %v), want (owner-1, true)", uid, ok)
+	}
+
+	if _, ok := firstOwnerUID(&batchv1.Job{}); ok {
+		t.Fatalf("firstOwnerUID() on ownerless job should return ok = false")
+	}
+}
+
+// newTestKleaner builds a Kleaner backed by a fake clientset and a job store
+// both seeded with jobs, suitable for exercising pruneJobsByOwner without a
+// real apiserver.
+func newTestKleaner(t *testing.T, jobs []*batchv1.Job, keepLastN int) (*Kleaner, *fake.Clientset) {
+	t.Helper()
+	objs := make([]interface{}, 0, len(jobs))
+	for _, job := range jobs {
+		objs = append(objs, job.DeepCopyObject())
+	}
+	clientset := fake.NewSimpleClientset()
+	for _, job := range jobs {
+		if _, err := clientset.BatchV1().Jobs(job.Namespace).Create(context.Background(), job, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("seeding fake clientset: %v", err)
+		}
+	}
+	kleaner := NewKleaner(context.Background(), clientset, Config{
+		Namespace:         "default",
+		KeepLastNPerOwner: keepLastN,
+	})
+	for _, obj := range objs {
+		if err := kleaner.jobInformer.GetStore().Add(obj); err != nil {
+			t.Fatalf("seeding job store: %v", err)
+		}
+	}
+	return kleaner, clientset
+}
+
+func completedJob(name string, ownerUID types.UID, completedAgo time.Duration) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			UID:             types.UID(name),
+			OwnerReferences: []metav1.OwnerReference{{UID: ownerUID}},
+		},
+		Status: batchv1.JobStatus{
+			CompletionTime: &metav1.Time{Time: time.Now().Add(-completedAgo)},
+		},
+	}
+}
+
+func TestPruneJobsByOwnerDeletesOlderThanN(t *testing.T) {
+	owner := types.UID("workflow-1")
+	oldest := completedJob("run-1", owner, 3*time.Hour)
+	middle := completedJob("run-2", owner, 2*time.Hour)
+	newest := completedJob("run-3", owner, time.Hour)
+
+	kleaner, clientset := newTestKleaner(t, []*batchv1.Job{oldest, middle, newest}, 2)
+
+	if deletedSelf := kleaner.pruneJobsByOwner(newest); deletedSelf {
+		t.Fatalf("pruneJobsByOwner() reported the newest job as deleted")
+	}
+
+	remaining, err := clientset.BatchV1().Jobs("default").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("listing remaining jobs: %v", err)
+	}
+	if len(remaining.Items) != 2 {
+		t.Fatalf("got %d remaining jobs, want 2", len(remaining.Items))
+	}
+	for _, job := range remaining.Items {
+		if job.Name == oldest.Name {
+			t.Errorf("oldest job %q should have been pruned", oldest.Name)
+		}
+	}
+}
+
+func TestPruneJobsByOwnerAtLimitIsNoop(t *testing.T) {
+	owner := types.UID("workflow-2")
+	first := completedJob("run-1", owner, 2*time.Hour)
+	second := completedJob("run-2", owner, time.Hour)
+
+	kleaner, clientset := newTestKleaner(t, []*batchv1.Job{first, second}, 2)
+
+	kleaner.pruneJobsByOwner(second)
+
+	remaining, err := clientset.BatchV1().Jobs("default").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("listing remaining jobs: %v", err)
+	}
+	if len(remaining.Items) != 2 {
+		t.Fatalf("got %d remaining jobs, want 2 (no pruning at the limit)", len(remaining.Items))
+	}
+}
+
+func TestPruneJobsByOwnerReportsDeletingTheGivenJob(t *testing.T) {
+	owner := types.UID("workflow-3")
+	oldest := completedJob("run-1", owner, 3*time.Hour)
+	newest := completedJob("run-2", owner, time.Hour)
+
+	kleaner, _ := newTestKleaner(t, []*batchv1.Job{oldest, newest}, 1)
+
+	if deletedSelf := kleaner.pruneJobsByOwner(oldest); !deletedSelf {
+		t.Fatalf("pruneJobsByOwner() should report that the given (oldest) job was itself pruned")
+	}
+}
+
+func TestPruneJobsByOwnerSkipsIgnored(t *testing.T) {
+	owner := types.UID("workflow-4")
+	oldest := completedJob("run-1", owner, 3*time.Hour)
+	oldest.Annotations = map[string]string{annotationIgnore: "true"}
+	newest := completedJob("run-2", owner, time.Hour)
+
+	kleaner, clientset := newTestKleaner(t, []*batchv1.Job{oldest, newest}, 1)
+
+	kleaner.pruneJobsByOwner(newest)
+
+	remaining, err := clientset.BatchV1().Jobs("default").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("listing remaining jobs: %v", err)
+	}
+	if len(remaining.Items) != 2 {
+		t.Fatalf("got %d remaining jobs, want 2 (ignored job must survive pruning)", len(remaining.Items))
+	}
+}
+
+func TestProcessQueueKeyDispatch(t *testing.T) {
+	job := completedJob("run-1", types.UID("owner"), time.Hour)
+	kleaner, _ := newTestKleaner(t, []*batchv1.Job{job}, 0)
+
+	if err := kleaner.processQueueKey(queueKey{kind: "job", key: "default/run-1"}); err != nil {
+		t.Errorf("processQueueKey(job) error = %v", err)
+	}
+	if err := kleaner.processQueueKey(queueKey{kind: "job", key: "default/missing"}); err != nil {
+		t.Errorf("processQueueKey(job, missing) should be a no-op, got error = %v", err)
+	}
+	if err := kleaner.processQueueKey(queueKey{kind: "pod", key: "default/missing"}); err != nil {
+		t.Errorf("processQueueKey(pod, missing) should be a no-op, got error = %v", err)
+	}
+	if err := kleaner.processQueueKey(queueKey{kind: "bogus", key: "default/run-1"}); err == nil {
+		t.Errorf("processQueueKey() with an unknown kind should return an error")
+	}
+}